@@ -0,0 +1,273 @@
+package hashmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// cmTable - снимок состояния ConcurrentMap: B и buckets должны меняться
+// вместе, поэтому они публикуются читателям одним атомарным указателем,
+// а не по отдельности. buckets хранит указатели на бакеты, а не сами
+// бакеты по значению, - это позволяет писателю клонировать ровно одну
+// цепочку бакета (структурное совместное использование остальных), а не
+// всю таблицу целиком на каждую запись.
+type cmTable[K comparable, V any] struct {
+	B       uint8
+	buckets []*bmap[K, V]
+}
+
+// entry - пара ключ/значение, используется только при полном
+// перестроении таблицы в ConcurrentMap.growAndInsert.
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// ConcurrentMap - потокобезопасный вариант Map: Get не требует
+// блокировки и читает консистентный снимок таблицы через atomic.Pointer.
+// Put/Delete берут writer-лок и клонируют только ту цепочку бакета,
+// которую меняют, - остальные бакеты публикуемого снимка продолжают
+// указывать на те же, не тронутые структуры, что и старый снимок.
+// Полное перестроение таблицы (как раньше делалось на каждую запись)
+// происходит только тогда, когда вставка действительно требует роста -
+// см. growAndInsert. Такой copy-on-write подход отдаёт предпочтение
+// быстрому чтению перед быстрой записью - см. бенчмарки в
+// concurrent_bench_test.go, сравнивающие это с sync.Map на разных
+// профилях нагрузки.
+type ConcurrentMap[K comparable, V any] struct {
+	hasher Hasher[K]
+	seed   uint64
+	count  atomic.Int64
+
+	mu    sync.Mutex // сериализует писателей; читатели его не видят
+	table atomic.Pointer[cmTable[K, V]]
+}
+
+// NewConcurrent создаёт пустую ConcurrentMap, заранее подготовленную под
+// hint элементов.
+func NewConcurrent[K comparable, V any](hint int) *ConcurrentMap[K, V] {
+	cm := &ConcurrentMap[K, V]{hasher: DefaultHasher[K](), seed: randomSeed()}
+
+	b := bucketsForHint(hint)
+	cm.table.Store(&cmTable[K, V]{B: b, buckets: newEmptyBuckets[K, V](bucketShift(b))})
+
+	return cm
+}
+
+// newEmptyBuckets выделяет n пустых бакетов, каждый - отдельный *bmap, так
+// чтобы в дальнейшем их можно было клонировать и заменять по одному.
+func newEmptyBuckets[K comparable, V any](n uintptr) []*bmap[K, V] {
+	buckets := make([]*bmap[K, V], n)
+	for i := range buckets {
+		buckets[i] = &bmap[K, V]{}
+	}
+	return buckets
+}
+
+// Get ищет значение по ключу без блокировок: он лишь берёт текущий
+// опубликованный снимок таблицы и читает его, не трогая mu. Снимок,
+// однажды опубликованный, больше не мутируется - его бакеты только
+// заменяют по одному клоном, поэтому конкурентный Put не может испортить
+// то, что Get в этот момент читает.
+func (cm *ConcurrentMap[K, V]) Get(key K) (V, bool) {
+	t := cm.table.Load()
+	hash := cm.hasher.Hash(key, cm.seed)
+	top := tophash(hash)
+	idx := uintptr(hash) & bucketMask(bucketShift(t.B))
+	return search(t.buckets[idx], top, key)
+}
+
+// Put добавляет или обновляет значение по ключу.
+func (cm *ConcurrentMap[K, V]) Put(key K, value V) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	old := cm.table.Load()
+	hash := cm.hasher.Hash(key, cm.seed)
+	top := tophash(hash)
+	idx := uintptr(hash) & bucketMask(bucketShift(old.B))
+
+	// Обновление существующего ключа не меняет количество элементов и
+	// никогда не требует роста - достаточно клонировать и заменить одну
+	// цепочку бакета.
+	if _, ok := search(old.buckets[idx], top, key); ok {
+		clone := cloneBucketChain(old.buckets[idx])
+		placeInBucket(clone, top, key, value)
+		cm.publishBucket(old, idx, clone)
+		return
+	}
+
+	if overLoadFactor(int(cm.count.Load())+1, old.B) {
+		cm.growAndInsert(old, key, value)
+		cm.count.Add(1)
+		return
+	}
+
+	clone := cloneBucketChain(old.buckets[idx])
+	placeInBucket(clone, top, key, value)
+	cm.publishBucket(old, idx, clone)
+	cm.count.Add(1)
+}
+
+// Delete удаляет ключ из таблицы, если он там был.
+func (cm *ConcurrentMap[K, V]) Delete(key K) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	old := cm.table.Load()
+	hash := cm.hasher.Hash(key, cm.seed)
+	top := tophash(hash)
+	idx := uintptr(hash) & bucketMask(bucketShift(old.B))
+
+	if _, ok := search(old.buckets[idx], top, key); !ok {
+		return
+	}
+
+	clone := cloneBucketChain(old.buckets[idx])
+	removeFromBucket(clone, top, key)
+	cm.publishBucket(old, idx, clone)
+	cm.count.Add(-1)
+}
+
+// Len возвращает количество элементов в таблице.
+func (cm *ConcurrentMap[K, V]) Len() int {
+	return int(cm.count.Load())
+}
+
+// Range проходит по снимку таблицы, актуальному на момент вызова; записи,
+// опубликованные уже во время Range, в обходе могут не участвовать -
+// как и у sync.Map.Range.
+func (cm *ConcurrentMap[K, V]) Range(f func(key K, value V) bool) {
+	t := cm.table.Load()
+	for bi := range t.buckets {
+		b := t.buckets[bi]
+		for b != nil {
+			for i := 0; i < bucketCnt; i++ {
+				if b.tophash[i] == 0 {
+					continue
+				}
+				if !f(b.keys[i], b.values[i]) {
+					return
+				}
+			}
+			b = b.overflow
+		}
+	}
+}
+
+// cloneBucketChain делает глубокую копию цепочки бакета (сам бакет и все
+// его overflow-бакеты), чтобы её можно было мутировать, не трогая старый,
+// уже опубликованный снимок, который могут читать конкурентные Get.
+func cloneBucketChain[K comparable, V any](b *bmap[K, V]) *bmap[K, V] {
+	if b == nil {
+		return nil
+	}
+	clone := &bmap[K, V]{tophash: b.tophash, keys: b.keys, values: b.values}
+	clone.overflow = cloneBucketChain(b.overflow)
+	return clone
+}
+
+// removeFromBucket очищает слот с ключом key в уже клонированной цепочке
+// бакета b (клон принадлежит только этому вызову, поэтому мутировать его
+// на месте безопасно).
+func removeFromBucket[K comparable, V any](b *bmap[K, V], top uint8, key K) {
+	for b != nil {
+		for i := 0; i < bucketCnt; i++ {
+			if b.tophash[i] == top && b.keys[i] == key {
+				var zeroK K
+				var zeroV V
+				b.tophash[i] = 0
+				b.keys[i] = zeroK
+				b.values[i] = zeroV
+				return
+			}
+		}
+		b = b.overflow
+	}
+}
+
+// publishBucket публикует новую таблицу, в которой заменён только бакет
+// idx (на newHead), - остальные бакеты продолжают указывать на те же
+// структуры, что и old, поэтому стоимость публикации - O(число бакетов),
+// а не O(число элементов).
+func (cm *ConcurrentMap[K, V]) publishBucket(old *cmTable[K, V], idx uintptr, newHead *bmap[K, V]) {
+	buckets := make([]*bmap[K, V], len(old.buckets))
+	copy(buckets, old.buckets)
+	buckets[idx] = newHead
+
+	cm.table.Store(&cmTable[K, V]{B: old.B, buckets: buckets})
+}
+
+// growAndInsert строит таблицу заново из всех текущих записей плюс новая
+// пара key/value, выбирая B не меньше old.B. Это единственный путь,
+// который стоит O(число элементов), и выполняется он только тогда, когда
+// вставка действительно переполняет таблицу, а не на каждый Put.
+func (cm *ConcurrentMap[K, V]) growAndInsert(old *cmTable[K, V], key K, value V) {
+	entries := collectEntries(old)
+	entries = append(entries, entry[K, V]{key, value})
+
+	b := old.B
+	for overLoadFactor(len(entries), b) {
+		b++
+	}
+
+	buckets := newEmptyBuckets[K, V](bucketShift(b))
+	for _, e := range entries {
+		hash := cm.hasher.Hash(e.key, cm.seed)
+		idx := uintptr(hash) & bucketMask(bucketShift(b))
+		placeInBucket(buckets[idx], tophash(hash), e.key, e.value)
+	}
+
+	cm.table.Store(&cmTable[K, V]{B: b, buckets: buckets})
+}
+
+// collectEntries достаёт все пары ключ/значение из снимка таблицы.
+func collectEntries[K comparable, V any](t *cmTable[K, V]) []entry[K, V] {
+	entries := make([]entry[K, V], 0, len(t.buckets))
+	for bi := range t.buckets {
+		b := t.buckets[bi]
+		for b != nil {
+			for i := 0; i < bucketCnt; i++ {
+				if b.tophash[i] == 0 {
+					continue
+				}
+				entries = append(entries, entry[K, V]{b.keys[i], b.values[i]})
+			}
+			b = b.overflow
+		}
+	}
+	return entries
+}
+
+// placeInBucket кладёт key/value в первый свободный слот цепочки бакета
+// b, обновляя значение на месте, если key там уже есть, и при
+// необходимости заводя новый overflow-бакет.
+func placeInBucket[K comparable, V any](b *bmap[K, V], top uint8, key K, value V) {
+	var insertAt *bmap[K, V]
+	var insertSlot = -1
+
+	for {
+		for i := 0; i < bucketCnt; i++ {
+			if b.tophash[i] == top && b.keys[i] == key {
+				b.values[i] = value
+				return
+			}
+			if insertSlot == -1 && b.tophash[i] == 0 {
+				insertAt, insertSlot = b, i
+			}
+		}
+		if b.overflow == nil {
+			break
+		}
+		b = b.overflow
+	}
+
+	if insertAt == nil {
+		b.overflow = &bmap[K, V]{}
+		insertAt, insertSlot = b.overflow, 0
+	}
+
+	insertAt.tophash[insertSlot] = top
+	insertAt.keys[insertSlot] = key
+	insertAt.values[insertSlot] = value
+}
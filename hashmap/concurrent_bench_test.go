@@ -0,0 +1,121 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+)
+
+const benchKeySpace = 1 << 11
+
+func fillConcurrentMap(n int) *ConcurrentMap[int, int] {
+	cm := NewConcurrent[int, int](n)
+	for i := 0; i < n; i++ {
+		cm.Put(i, i)
+	}
+	return cm
+}
+
+func fillSyncMap(n int) *sync.Map {
+	var sm sync.Map
+	for i := 0; i < n; i++ {
+		sm.Store(i, i)
+	}
+	return &sm
+}
+
+// BenchmarkConcurrentMapReadMostly/BenchmarkSyncMapReadMostly: 95% Get, 5% Put.
+func BenchmarkConcurrentMapReadMostly(b *testing.B) {
+	cm := fillConcurrentMap(benchKeySpace)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := i % benchKeySpace
+			if i%20 == 0 {
+				cm.Put(key, key)
+			} else {
+				cm.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncMapReadMostly(b *testing.B) {
+	sm := fillSyncMap(benchKeySpace)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := i % benchKeySpace
+			if i%20 == 0 {
+				sm.Store(key, key)
+			} else {
+				sm.Load(key)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkConcurrentMapWriteMostly/BenchmarkSyncMapWriteMostly: всё время Put.
+func BenchmarkConcurrentMapWriteMostly(b *testing.B) {
+	cm := fillConcurrentMap(benchKeySpace)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := i % benchKeySpace
+			cm.Put(key, key)
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncMapWriteMostly(b *testing.B) {
+	sm := fillSyncMap(benchKeySpace)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := i % benchKeySpace
+			sm.Store(key, key)
+			i++
+		}
+	})
+}
+
+// BenchmarkConcurrentMapMixed/BenchmarkSyncMapMixed: 50/50 Get и Put.
+func BenchmarkConcurrentMapMixed(b *testing.B) {
+	cm := fillConcurrentMap(benchKeySpace)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := i % benchKeySpace
+			if i%2 == 0 {
+				cm.Put(key, key)
+			} else {
+				cm.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkSyncMapMixed(b *testing.B) {
+	sm := fillSyncMap(benchKeySpace)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := i % benchKeySpace
+			if i%2 == 0 {
+				sm.Store(key, key)
+			} else {
+				sm.Load(key)
+			}
+			i++
+		}
+	})
+}
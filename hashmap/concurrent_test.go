@@ -0,0 +1,103 @@
+package hashmap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentWriteDetectionOnMap(t *testing.T) {
+	m := New[int, int](0)
+	m.flags |= hashWriting // имитируем запись, уже идущую в другой горутине
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("Put did not panic while m.flags indicated a write in progress")
+		}
+	}()
+	m.Put(1, 1)
+}
+
+func TestConcurrentReadDetectionOnMap(t *testing.T) {
+	m := New[int, int](0)
+	m.flags |= hashWriting
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("Get did not panic while m.flags indicated a write in progress")
+		}
+	}()
+	m.Get(1)
+}
+
+func TestConcurrentMapBasics(t *testing.T) {
+	cm := NewConcurrent[string, int](0)
+
+	cm.Put("a", 1)
+	cm.Put("b", 2)
+	cm.Put("a", 10)
+
+	if v, ok := cm.Get("a"); !ok || v != 10 {
+		t.Fatalf("Get(a) = %d, %v, want 10, true", v, ok)
+	}
+	if got, want := cm.Len(), 2; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+
+	cm.Delete("a")
+	if _, ok := cm.Get("a"); ok {
+		t.Fatalf("Get(a) found a value after Delete")
+	}
+	if got, want := cm.Len(), 1; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+}
+
+// TestConcurrentMapStress запускает читателей и писателей одновременно;
+// её смысл - дать `go test -race` шанс поймать гонку в ConcurrentMap.
+func TestConcurrentMapStress(t *testing.T) {
+	cm := NewConcurrent[int, int](0)
+
+	const (
+		writers      = 8
+		readers      = 8
+		opsPerWriter = 500
+	)
+
+	var writersWG, readersWG sync.WaitGroup
+	stop := make(chan struct{})
+
+	writersWG.Add(writers)
+	for w := 0; w < writers; w++ {
+		w := w
+		go func() {
+			defer writersWG.Done()
+			for i := 0; i < opsPerWriter; i++ {
+				key := w*opsPerWriter + i
+				cm.Put(key, key)
+				cm.Get(key)
+				if i%3 == 0 {
+					cm.Delete(key)
+				}
+			}
+		}()
+	}
+
+	readersWG.Add(readers)
+	for r := 0; r < readers; r++ {
+		go func() {
+			defer readersWG.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					cm.Range(func(k, v int) bool { return true })
+				}
+			}
+		}()
+	}
+
+	writersWG.Wait()
+	close(stop)
+	readersWG.Wait()
+}
@@ -0,0 +1,154 @@
+package hashmap
+
+// loadFactorNum/loadFactorDen задают порог роста - среднее количество
+// элементов на бакет, после которого таблица считается перегруженной
+// (13/2 = 6.5, как в рантайме Go).
+const (
+	loadFactorNum = 13
+	loadFactorDen = 2
+)
+
+// overLoadFactor сообщает, не превышено ли среднее число элементов на
+// бакет после добавления ещё одного элемента.
+func overLoadFactor(count int, B uint8) bool {
+	return count > bucketCnt && uintptr(count) > loadFactorNum*bucketShift(B)/loadFactorDen
+}
+
+// tooManyOverflowBuckets сообщает, не расплодилось ли overflow-бакетов
+// не меньше, чем основных (для B > 15 порог остаётся 1<<15). В этом случае рост без увеличения B (same-size
+// growth) позволяет уплотнить цепочки, не тратя лишнюю память.
+func tooManyOverflowBuckets(noverflow uint16, B uint8) bool {
+	if B > 15 {
+		B = 15
+	}
+	return noverflow >= uint16(1)<<(B&15)
+}
+
+// oldBucketIndex возвращает индекс бакета в oldbuckets, из которого этот
+// хэш будет эвакуирован.
+func (m *Map[K, V]) oldBucketIndex(hash uint64) uintptr {
+	oldB := m.B
+	if !m.sameSizeGrow {
+		oldB--
+	}
+	return uintptr(hash) & bucketMask(bucketShift(oldB))
+}
+
+// hashGrow запускает рост таблицы: старые бакеты откладываются в
+// oldbuckets, а buckets становится новым, пустым массивом нужного
+// размера. Сами элементы переносятся не сразу, а постепенно, в
+// growWork/evacuate, по мере обращений к таблице.
+//
+// bigger == true значит, что таблица перегружена и B нужно увеличить
+// вдвое; bigger == false - это same-size growth, нужный только чтобы
+// уплотнить разросшиеся overflow-цепочки.
+func (m *Map[K, V]) hashGrow(bigger bool) {
+	oldBuckets := m.buckets
+
+	if bigger {
+		m.B++
+	}
+	m.sameSizeGrow = !bigger
+	m.oldbuckets = oldBuckets
+	m.evacuated = make([]bool, len(oldBuckets))
+	m.buckets = make([]bmap[K, V], bucketShift(m.B))
+	m.nevacuate = 0
+	m.noverflow = 0
+}
+
+// growWork переносит из старой таблицы в новую бакет, нужный для текущей
+// операции (targetOldIdx), а заодно продвигает общий прогресс эвакуации
+// на один бакет. Двух эвакуаций за вызов достаточно, чтобы таблица
+// гарантированно дорастала за конечное число Put/Delete, даже если все
+// они приходятся в один и тот же бакет.
+func (m *Map[K, V]) growWork(targetOldIdx uintptr) {
+	m.evacuate(targetOldIdx)
+	if m.oldbuckets != nil {
+		m.evacuate(m.nevacuate)
+	}
+}
+
+// evacuate переносит все записи бакета oldIdx (вместе с его overflow-
+// цепочкой) из oldbuckets в buckets и помечает его как эвакуированный.
+// При росте с увеличением B записи расходятся по двум новым бакетам в
+// зависимости от дополнительного бита хэша (low/high half); при
+// same-size growth все записи остаются на том же индексе, но собираются
+// в более короткую цепочку.
+func (m *Map[K, V]) evacuate(oldIdx uintptr) {
+	if m.oldbuckets == nil || oldIdx >= uintptr(len(m.oldbuckets)) || m.evacuated[oldIdx] {
+		return
+	}
+
+	b := &m.oldbuckets[oldIdx]
+	for b != nil {
+		for i := 0; i < bucketCnt; i++ {
+			if b.tophash[i] == 0 {
+				continue
+			}
+
+			key, value := b.keys[i], b.values[i]
+			hash := m.hash(key)
+
+			destIdx := oldIdx
+			if !m.sameSizeGrow {
+				oldB := m.B - 1
+				if uintptr(hash)&bucketShift(oldB) != 0 {
+					destIdx = oldIdx + bucketShift(oldB)
+				}
+			}
+
+			m.insertEvacuated(destIdx, tophash(hash), key, value)
+		}
+		b = b.overflow
+	}
+
+	m.evacuated[oldIdx] = true
+
+	for m.nevacuate < uintptr(len(m.oldbuckets)) && m.evacuated[m.nevacuate] {
+		m.nevacuate++
+	}
+	if m.nevacuate >= uintptr(len(m.oldbuckets)) {
+		m.oldbuckets = nil
+		m.evacuated = nil
+		m.sameSizeGrow = false
+		m.nevacuate = 0
+	}
+}
+
+// insertEvacuated кладёт уже посчитанный tophash/key/value в новую
+// таблицу во время эвакуации. В отличие от Put, она не проверяет условия
+// роста - buckets уже имеет целевой размер.
+func (m *Map[K, V]) insertEvacuated(idx uintptr, top uint8, key K, value V) {
+	b := &m.buckets[idx]
+	for {
+		for i := 0; i < bucketCnt; i++ {
+			if b.tophash[i] == 0 {
+				b.tophash[i] = top
+				b.keys[i] = key
+				b.values[i] = value
+				return
+			}
+		}
+		if b.overflow == nil {
+			b.overflow = &bmap[K, V]{}
+			m.noverflow++
+		}
+		b = b.overflow
+	}
+}
+
+// Load возвращает среднее количество элементов на бакет - ту же
+// величину, с которой сравнивается loadFactorNum/loadFactorDen.
+func (m *Map[K, V]) Load() float64 {
+	numBuckets := bucketShift(m.B)
+	if numBuckets == 0 {
+		return 0
+	}
+	return float64(m.count) / float64(numBuckets)
+}
+
+// OverflowCount возвращает число overflow-бакетов, выделенных для
+// текущей таблицы buckets (не считая ещё не эвакуированных oldbuckets).
+func (m *Map[K, V]) OverflowCount() int {
+	return int(m.noverflow)
+}
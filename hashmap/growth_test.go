@@ -0,0 +1,142 @@
+package hashmap
+
+import "testing"
+
+func TestGrowthPreservesAllEntries(t *testing.T) {
+	m := New[int, int](0)
+
+	const n = 5000
+	for i := 0; i < n; i++ {
+		m.Put(i, i*2)
+	}
+
+	if got := m.Len(); got != n {
+		t.Fatalf("Len() = %d, want %d", got, n)
+	}
+	for i := 0; i < n; i++ {
+		v, ok := m.Get(i)
+		if !ok || v != i*2 {
+			t.Fatalf("Get(%d) = %d, %v, want %d, true", i, v, ok, i*2)
+		}
+	}
+
+	seen := make(map[int]int, n)
+	m.Range(func(k, v int) bool {
+		seen[k] = v
+		return true
+	})
+	if len(seen) != n {
+		t.Fatalf("Range visited %d entries, want %d", len(seen), n)
+	}
+
+	if load := m.Load(); load > float64(loadFactorNum)/loadFactorDen {
+		t.Fatalf("Load() = %f, want <= %f", load, float64(loadFactorNum)/loadFactorDen)
+	}
+}
+
+func TestGrowthDuringDeletes(t *testing.T) {
+	m := New[int, int](0)
+
+	const n = 2000
+	for i := 0; i < n; i++ {
+		m.Put(i, i)
+	}
+	for i := 0; i < n; i += 2 {
+		m.Delete(i)
+	}
+
+	if got, want := m.Len(), n/2; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	for i := 0; i < n; i++ {
+		v, ok := m.Get(i)
+		if i%2 == 0 {
+			if ok {
+				t.Fatalf("Get(%d) found a deleted value %d", i, v)
+			}
+			continue
+		}
+		if !ok || v != i {
+			t.Fatalf("Get(%d) = %d, %v, want %d, true", i, v, ok, i)
+		}
+	}
+}
+
+// findKeysForBucket подбирает count различных int-ключей, которые реально
+// попадают в бакет bucketIdx таблицы m (с её текущим B и seed), перебирая
+// кандидатов по порядку. Нужно для детерминированного построения
+// разреженной overflow-цепочки в конкретном бакете в
+// TestSameSizeGrowthCompactsOverflow, не завися от удачного стечения
+// хэшей.
+func findKeysForBucket(t *testing.T, m *Map[int, int], bucketIdx uintptr, count int) []int {
+	t.Helper()
+
+	keys := make([]int, 0, count)
+	for candidate := 0; candidate < 1_000_000 && len(keys) < count; candidate++ {
+		if m.bucket(m.hash(candidate)) == bucketIdx {
+			keys = append(keys, candidate)
+		}
+	}
+	if len(keys) < count {
+		t.Fatalf("could not find %d keys hashing to bucket %d", count, bucketIdx)
+	}
+	return keys
+}
+
+// TestSameSizeGrowthCompactsOverflow строит в одном бакете цепочку из
+// overflow-бакетов, в каждом из которых живёт лишь один ключ (остальные
+// слоты пусты, как после точечных Delete) - именно такую разреженную
+// цепочку same-size growth должен уплотнить без изменения B. Цепочка
+// собирается напрямую через поля bmap (а не через Put/Delete), чтобы
+// тест детерминированно доводил noverflow до tooManyOverflowBuckets,
+// вместо того чтобы полагаться на случайное стечение хэшей.
+func TestSameSizeGrowthCompactsOverflow(t *testing.T) {
+	m := New[int, int](0)
+	m.B = 3
+	m.buckets = make([]bmap[int, int], bucketShift(m.B))
+
+	const bucketIdx = 0
+	const chainLen = 10 // 1 в основном бакете + 9 overflow, больше порога 1<<B=8
+	keys := findKeysForBucket(t, m, bucketIdx, chainLen)
+
+	b := &m.buckets[bucketIdx]
+	hash := m.hash(keys[0])
+	b.tophash[0], b.keys[0], b.values[0] = tophash(hash), keys[0], keys[0]
+	for _, k := range keys[1:] {
+		ov := &bmap[int, int]{}
+		hash = m.hash(k)
+		ov.tophash[0], ov.keys[0], ov.values[0] = tophash(hash), k, k
+		b.overflow = ov
+		b = ov
+	}
+	m.noverflow = uint16(len(keys) - 1)
+	m.count = len(keys)
+
+	if !tooManyOverflowBuckets(m.noverflow, m.B) {
+		t.Fatalf("test setup is broken: noverflow=%d does not exceed the 1<<B=%d threshold", m.noverflow, bucketShift(m.B))
+	}
+	if overLoadFactor(m.count, m.B) {
+		t.Fatalf("test setup is broken: count=%d already trips overLoadFactor, growth would double B instead of same-size growing", m.count)
+	}
+	before := m.OverflowCount()
+
+	m.hashGrow(false)
+	for m.oldbuckets != nil {
+		m.evacuate(m.nevacuate)
+	}
+
+	if m.B != 3 {
+		t.Fatalf("B changed to %d, want unchanged at 3 for a same-size growth", m.B)
+	}
+	if after := m.OverflowCount(); after >= before {
+		t.Fatalf("OverflowCount() = %d after same-size growth, want less than %d", after, before)
+	}
+	if got, want := m.Len(), chainLen; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	for _, k := range keys {
+		if v, ok := m.Get(k); !ok || v != k {
+			t.Fatalf("Get(%d) = %d, %v, want %d, true", k, v, ok, k)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package hashmap
+
+// Hasher считает хэш ключа типа K с учётом seed. seed задаётся случайно
+// при создании каждой Map (см. New в hashmap.go), поэтому даже для
+// заранее известного набора ключей конкретные номера бакетов заранее не
+// предсказать - свойство #4 из заметок в main.go ("нельзя подобрать
+// ключи так, чтобы все попали в один бакет").
+type Hasher[K comparable] interface {
+	Hash(key K, seed uint64) uint64
+}
+
+// Integer - типы, для которых можно просто привести значение к uint64 и
+// перемешать его, без обращения к содержимому по указателю.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// mix64 - финализатор splitmix64, даёт хорошее лавинное перемешивание
+// битов для маленьких целочисленных ключей.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// fnv1a - вариант FNV-1a, затравленный seed вместо стандартного offset
+// basis, чтобы хэш байтовых ключей (строк) тоже зависел от seed.
+func fnv1a(data []byte, seed uint64) uint64 {
+	const prime = 1099511628211
+	h := (14695981039346656037 ^ seed) * prime
+	for _, b := range data {
+		h ^= uint64(b)
+		h *= prime
+	}
+	return h
+}
+
+// IntHasher - Hasher для любых целочисленных типов.
+type IntHasher[K Integer] struct{}
+
+func (IntHasher[K]) Hash(key K, seed uint64) uint64 {
+	return mix64(uint64(key) ^ seed)
+}
+
+// StringHasher - Hasher для строк.
+type StringHasher struct{}
+
+func (StringHasher) Hash(key string, seed uint64) uint64 {
+	return fnv1a([]byte(key), seed)
+}
+
+// DefaultHasher подбирает Hasher для K: для строк и целых чисел
+// используются быстрые специализированные реализации выше, а для
+// остальных comparable-типов - RuntimeHasher, который достаёт из рантайма
+// Go тот же хэшер, которым пользуется встроенный map[K]V. []byte не
+// входит в этот список: он не удовлетворяет comparable, поэтому
+// Hasher[[]byte] (и любой Map[[]byte, V]) не скомпилируется в принципе -
+// для ключей на основе байтов заведите comparable-обёртку (например,
+// [N]byte) и воспользуйтесь RuntimeHasher через default-ветку ниже.
+func DefaultHasher[K comparable]() Hasher[K] {
+	var zero K
+	switch any(zero).(type) {
+	case string:
+		return any(StringHasher{}).(Hasher[K])
+	case int:
+		return any(IntHasher[int]{}).(Hasher[K])
+	case int8:
+		return any(IntHasher[int8]{}).(Hasher[K])
+	case int16:
+		return any(IntHasher[int16]{}).(Hasher[K])
+	case int32:
+		return any(IntHasher[int32]{}).(Hasher[K])
+	case int64:
+		return any(IntHasher[int64]{}).(Hasher[K])
+	case uint:
+		return any(IntHasher[uint]{}).(Hasher[K])
+	case uint8:
+		return any(IntHasher[uint8]{}).(Hasher[K])
+	case uint16:
+		return any(IntHasher[uint16]{}).(Hasher[K])
+	case uint32:
+		return any(IntHasher[uint32]{}).(Hasher[K])
+	case uint64:
+		return any(IntHasher[uint64]{}).(Hasher[K])
+	case uintptr:
+		return any(IntHasher[uintptr]{}).(Hasher[K])
+	default:
+		return NewRuntimeHasher[K]()
+	}
+}
@@ -0,0 +1,101 @@
+package hashmap
+
+import (
+	"fmt"
+	"testing"
+)
+
+// point не входит ни в один из специализированных случаев DefaultHasher,
+// поэтому для него должен быть выбран RuntimeHasher.
+type point struct {
+	x, y int32
+}
+
+func TestDefaultHasherPicksRuntimeHasherForStructKeys(t *testing.T) {
+	h := DefaultHasher[point]()
+	if _, ok := h.(*RuntimeHasher[point]); !ok {
+		t.Fatalf("DefaultHasher[point]() = %T, want *RuntimeHasher[point]", h)
+	}
+
+	m := New[point, string](0)
+	m.Put(point{1, 2}, "a")
+	m.Put(point{3, 4}, "b")
+
+	if v, ok := m.Get(point{1, 2}); !ok || v != "a" {
+		t.Fatalf("Get({1,2}) = %q, %v, want a, true", v, ok)
+	}
+	if v, ok := m.Get(point{3, 4}); !ok || v != "b" {
+		t.Fatalf("Get({3,4}) = %q, %v, want b, true", v, ok)
+	}
+	if _, ok := m.Get(point{9, 9}); ok {
+		t.Fatalf("Get({9,9}) found a value, want not found")
+	}
+}
+
+func TestSeedChangesBucketLayout(t *testing.T) {
+	// Два разных Map с одним и тем же набором ключей должны (почти
+	// наверняка) раскладывать их по бакетам по-разному - иначе атакующий,
+	// знающий алгоритм хэширования, но не seed, мог бы заранее подобрать
+	// ключи, коллизирующие в одном бакете у любого инстанса Map.
+	keys := make([]string, 256)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	layout := func(m *Map[string, int]) []uint64 {
+		out := make([]uint64, len(keys))
+		for i, k := range keys {
+			out[i] = uint64(m.bucket(m.hash(k)))
+		}
+		return out
+	}
+
+	a := New[string, int](len(keys))
+	b := New[string, int](len(keys))
+
+	if layoutsEqual(layout(a), layout(b)) {
+		t.Fatalf("two independently seeded maps produced identical bucket layouts")
+	}
+}
+
+func layoutsEqual(a, b []uint64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// BenchmarkCollisionAttackKeys использует ключи, которые под обычным
+// нессиженным FNV-1a все коллизируют в одном бакете (ключи вида key-N,
+// отобранные так, чтобы младшие 8 бит hash были нулевыми при seed=0, а
+// New(2000) заводит 256 бакетов). Со случайным per-map
+// seed эта подгонка перестаёт работать, и вставка остаётся около O(1) на
+// элемент вместо вырождения в O(n) на один бакет.
+func BenchmarkCollisionAttackKeys(b *testing.B) {
+	const n = 2000
+	keys := adversarialKeys(n)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := New[string, int](n)
+		for _, k := range keys {
+			m.Put(k, 1)
+		}
+	}
+}
+
+// adversarialKeys строит ключи, коллизирующие друг с другом при seed=0
+// под fnv1a (аналог атаки, которую свойство #4 в main.go просит
+// предотвратить).
+func adversarialKeys(n int) []string {
+	keys := make([]string, 0, n)
+	for suffix := 0; len(keys) < n; suffix++ {
+		k := fmt.Sprintf("key-%d", suffix)
+		if fnv1a([]byte(k), 0)&0xff == 0 {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
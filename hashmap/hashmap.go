@@ -0,0 +1,311 @@
+// Package hashmap - рабочая реализация хэш-таблицы, построенной по мотивам
+// внутреннего устройства map в рантайме Go (см. заметки в main.go в корне
+// репозитория). В отличие от main.go, где разбирались только формулы
+// bucketShift/bucketMask, здесь уже настоящая структура данных, которой
+// можно пользоваться: Get/Put/Delete/Len/Range.
+package hashmap
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// bucketCnt - количество слотов в одном бакете, как и в рантайме Go.
+const bucketCnt = 8
+
+// hashWriting - бит флага m.flags, выставленный на время записи. Map не
+// рассчитана на конкурентное использование (для этого есть
+// ConcurrentMap, см. concurrent.go); этот флаг - дешёвая, по-настоящему
+// не синхронизированная проверка "на всякий случай", так же как и в
+// рантайме Go: она не гарантирует обнаружение гонки при каждом запуске,
+// но ловит большинство случаев и паникует вместо тихой порчи данных.
+const hashWriting = 1
+
+// minTopHash - минимальное "рабочее" значение tophash. Значения меньше
+// этого порога зарезервированы под служебные пометки (в рантайме Go ими
+// помечают пустые и эвакуированные слоты); 0 у нас всегда означает "слот
+// свободен".
+const minTopHash = 1
+
+// bmap - бакет хэш-таблицы. Поля расположены так же, как в рантайме Go:
+// tophash[8], keys[8], values[8], а затем указатель на overflow-бакет.
+// Такой порядок (k/k/k/k/v/v/v/v, а не k/v/k/v/...) не требует паддинга
+// между ключом и значением на каждой позиции, поэтому одинаково хорошо
+// работает и для map[int64]int8, и для любой другой пары типов.
+type bmap[K comparable, V any] struct {
+	tophash  [bucketCnt]uint8
+	keys     [bucketCnt]K
+	values   [bucketCnt]V
+	overflow *bmap[K, V]
+}
+
+// Map - хэш-таблица с бакетированным хранением, дженерик-аналог map[K]V.
+type Map[K comparable, V any] struct {
+	count   int
+	B       uint8 // количество бакетов хранится как log_2(n), см. main.go
+	buckets []bmap[K, V]
+	hasher  Hasher[K]
+	seed    uint64
+	flags   uint8
+
+	// Поля для инкрементального роста, см. growth.go: пока идёт рост,
+	// старый массив бакетов живёт рядом с новым и опустошается
+	// постепенно, вместо одной большой паузы на rehash всей таблицы.
+	oldbuckets   []bmap[K, V]
+	evacuated    []bool
+	nevacuate    uintptr
+	sameSizeGrow bool
+	noverflow    uint16
+}
+
+// New создаёт пустую Map, заранее подготовленную под hint элементов
+// (аналогично makemap в рантайме Go). hint <= 0 означает "без подсказки".
+func New[K comparable, V any](hint int) *Map[K, V] {
+	m := &Map[K, V]{hasher: DefaultHasher[K](), seed: randomSeed()}
+
+	b := bucketsForHint(hint)
+	m.B = b
+	m.buckets = make([]bmap[K, V], bucketShift(b))
+
+	return m
+}
+
+// bucketsForHint считает минимальный B, при котором 2^B бакетов по
+// bucketCnt слотов вмещают hint элементов (используется New и
+// NewConcurrent). Порог роста loadFactorNum/loadFactorDen (6.5 на бакет)
+// здесь не учитывается, поэтому рост может начаться раньше, чем таблица
+// наберёт hint элементов. hint <= 0
+// трактуется как "без подсказки" и даёт B = 0 - сравнение идёт по
+// обычному int, поэтому отрицательный hint не оборачивается в
+// исполинский uintptr, как это было бы при прямом uintptr(hint).
+func bucketsForHint(hint int) uint8 {
+	if hint < 0 {
+		hint = 0
+	}
+
+	var b uint8
+	for bucketShift(b)*bucketCnt < uintptr(hint) {
+		b++
+	}
+	return b
+}
+
+// bucketShift возвращает 2^b, то есть число бакетов, хранящихся как log_2.
+func bucketShift(b uint8) uintptr {
+	return 1 << b
+}
+
+// bucketMask возвращает маску младших бит хэша для выбора бакета, см.
+// разбор в main.go.
+func bucketMask(numBuckets uintptr) uintptr {
+	return numBuckets - 1
+}
+
+// hash считает хэш ключа через m.hasher, передавая ему случайный seed
+// этой конкретной Map.
+func (m *Map[K, V]) hash(key K) uint64 {
+	return m.hasher.Hash(key, m.seed)
+}
+
+// randomSeed читает случайный seed из crypto/rand. Предсказуемый seed
+// позволил бы заранее подобрать ключи, которые все лягут в один бакет
+// (см. требование безопасности в main.go), поэтому обычный math/rand
+// здесь не подходит.
+func randomSeed() uint64 {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		panic("hashmap: failed to read random seed: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// tophash - верхние 8 бит хэша, используются как быстрый фильтр, чтобы не
+// сравнивать ключи на каждом слоте бакета. 0 зарезервирован под "слот
+// пуст", поэтому попавшие в этот диапазон значения сдвигаем вверх.
+func tophash(hash uint64) uint8 {
+	top := uint8(hash >> 56)
+	if top < minTopHash {
+		top += minTopHash
+	}
+	return top
+}
+
+// bucket возвращает индекс бакета для хэша.
+func (m *Map[K, V]) bucket(hash uint64) uintptr {
+	return uintptr(hash) & bucketMask(bucketShift(m.B))
+}
+
+// Get возвращает значение по ключу и флаг, было ли оно найдено.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if m.buckets == nil {
+		return zero, false
+	}
+	if m.flags&hashWriting != 0 {
+		panic("hashmap: concurrent map read and map write")
+	}
+
+	hash := m.hash(key)
+	top := tophash(hash)
+
+	if m.oldbuckets != nil {
+		oldIdx := m.oldBucketIndex(hash)
+		if !m.evacuated[oldIdx] {
+			return search(&m.oldbuckets[oldIdx], top, key)
+		}
+	}
+
+	return search(&m.buckets[m.bucket(hash)], top, key)
+}
+
+// search проходит по цепочке бакета b (вместе с overflow) в поисках key с
+// уже посчитанным tophash.
+func search[K comparable, V any](b *bmap[K, V], top uint8, key K) (V, bool) {
+	for b != nil {
+		for i := 0; i < bucketCnt; i++ {
+			if b.tophash[i] == top && b.keys[i] == key {
+				return b.values[i], true
+			}
+		}
+		b = b.overflow
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Put добавляет или обновляет значение по ключу.
+func (m *Map[K, V]) Put(key K, value V) {
+	if m.buckets == nil {
+		m.buckets = make([]bmap[K, V], 1)
+	}
+	if m.flags&hashWriting != 0 {
+		panic("hashmap: concurrent map writes")
+	}
+	m.flags |= hashWriting
+	defer func() { m.flags &^= hashWriting }()
+
+	hash := m.hash(key)
+	top := tophash(hash)
+
+again:
+	if m.oldbuckets != nil {
+		m.growWork(m.oldBucketIndex(hash))
+	}
+
+	b := &m.buckets[m.bucket(hash)]
+
+	var insertAt *bmap[K, V]
+	var insertSlot int = -1
+
+	for {
+		for i := 0; i < bucketCnt; i++ {
+			if b.tophash[i] == top && b.keys[i] == key {
+				b.values[i] = value
+				return
+			}
+			if insertSlot == -1 && b.tophash[i] == 0 {
+				insertAt, insertSlot = b, i
+			}
+		}
+		if b.overflow == nil {
+			break
+		}
+		b = b.overflow
+	}
+
+	if insertAt == nil {
+		if m.oldbuckets == nil && (overLoadFactor(m.count+1, m.B) || tooManyOverflowBuckets(m.noverflow, m.B)) {
+			m.hashGrow(overLoadFactor(m.count+1, m.B))
+			goto again
+		}
+		b.overflow = &bmap[K, V]{}
+		m.noverflow++
+		insertAt, insertSlot = b.overflow, 0
+	}
+
+	insertAt.tophash[insertSlot] = top
+	insertAt.keys[insertSlot] = key
+	insertAt.values[insertSlot] = value
+	m.count++
+}
+
+// Delete удаляет ключ из таблицы, если он там был.
+func (m *Map[K, V]) Delete(key K) {
+	if m.buckets == nil {
+		return
+	}
+	if m.flags&hashWriting != 0 {
+		panic("hashmap: concurrent map writes")
+	}
+	m.flags |= hashWriting
+	defer func() { m.flags &^= hashWriting }()
+
+	hash := m.hash(key)
+	top := tophash(hash)
+
+	if m.oldbuckets != nil {
+		m.growWork(m.oldBucketIndex(hash))
+	}
+
+	b := &m.buckets[m.bucket(hash)]
+
+	for b != nil {
+		for i := 0; i < bucketCnt; i++ {
+			if b.tophash[i] == top && b.keys[i] == key {
+				var zeroK K
+				var zeroV V
+				b.tophash[i] = 0
+				b.keys[i] = zeroK
+				b.values[i] = zeroV
+				m.count--
+				return
+			}
+		}
+		b = b.overflow
+	}
+}
+
+// Len возвращает количество элементов в таблице.
+func (m *Map[K, V]) Len() int {
+	return m.count
+}
+
+// Range проходит по всем парам ключ/значение в неопределённом порядке,
+// пока f не вернёт false (по аналогии с sync.Map.Range).
+func (m *Map[K, V]) Range(f func(key K, value V) bool) {
+	// Бакеты, ещё не эвакуированные из старой таблицы, живут только там -
+	// в новой их пока нет, их нужно обойти отдельно, чтобы не потерять.
+	for oi := range m.oldbuckets {
+		if m.evacuated[oi] {
+			continue
+		}
+		b := &m.oldbuckets[oi]
+		for b != nil {
+			for i := 0; i < bucketCnt; i++ {
+				if b.tophash[i] == 0 {
+					continue
+				}
+				if !f(b.keys[i], b.values[i]) {
+					return
+				}
+			}
+			b = b.overflow
+		}
+	}
+
+	for bi := range m.buckets {
+		b := &m.buckets[bi]
+		for b != nil {
+			for i := 0; i < bucketCnt; i++ {
+				if b.tophash[i] == 0 {
+					continue
+				}
+				if !f(b.keys[i], b.values[i]) {
+					return
+				}
+			}
+			b = b.overflow
+		}
+	}
+}
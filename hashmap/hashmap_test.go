@@ -0,0 +1,87 @@
+package hashmap
+
+import "testing"
+
+func TestPutGetDelete(t *testing.T) {
+	m := New[string, int](0)
+
+	m.Put("a", 1)
+	m.Put("b", 2)
+	m.Put("a", 10) // overwrite
+
+	if v, ok := m.Get("a"); !ok || v != 10 {
+		t.Fatalf("Get(a) = %d, %v, want 10, true", v, ok)
+	}
+	if v, ok := m.Get("b"); !ok || v != 2 {
+		t.Fatalf("Get(b) = %d, %v, want 2, true", v, ok)
+	}
+	if _, ok := m.Get("missing"); ok {
+		t.Fatalf("Get(missing) found a value, want not found")
+	}
+	if got, want := m.Len(), 2; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+
+	m.Delete("a")
+	if _, ok := m.Get("a"); ok {
+		t.Fatalf("Get(a) found a value after Delete")
+	}
+	if got, want := m.Len(), 1; got != want {
+		t.Fatalf("Len() = %d after Delete, want %d", got, want)
+	}
+}
+
+func TestOverflowBuckets(t *testing.T) {
+	m := New[int, int](0)
+
+	const n = 200
+	for i := 0; i < n; i++ {
+		m.Put(i, i*i)
+	}
+
+	if got, want := m.Len(), n; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	for i := 0; i < n; i++ {
+		v, ok := m.Get(i)
+		if !ok || v != i*i {
+			t.Fatalf("Get(%d) = %d, %v, want %d, true", i, v, ok, i*i)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	m := New[int, int](0)
+	want := map[int]int{1: 10, 2: 20, 3: 30}
+	for k, v := range want {
+		m.Put(k, v)
+	}
+
+	got := make(map[int]int)
+	m.Range(func(k, v int) bool {
+		got[k] = v
+		return true
+	})
+
+	if len(got) != len(want) {
+		t.Fatalf("Range visited %d entries, want %d", len(got), len(want))
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("Range missed %d=%d, got %v", k, v, got)
+		}
+	}
+}
+
+func TestMismatchedKeyValueSizes(t *testing.T) {
+	m := New[int64, int8](0)
+	m.Put(1, 7)
+	m.Put(2, -7)
+
+	if v, ok := m.Get(int64(1)); !ok || v != 7 {
+		t.Fatalf("Get(1) = %d, %v, want 7, true", v, ok)
+	}
+	if v, ok := m.Get(int64(2)); !ok || v != -7 {
+		t.Fatalf("Get(2) = %d, %v, want -7, true", v, ok)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !unix
+
+package hashmap
+
+import "os"
+
+// MMapView - read-only представление таблицы. На платформах без mmap(2)
+// (см. mmap_unix.go) это представление просто читает файл целиком в
+// память при открытии: API совпадает, но "без лишней копии в куче" здесь
+// не выполняется.
+type MMapView[K comparable, V any] struct {
+	*Map[K, V]
+}
+
+// MMap на не-unix платформах читает файл целиком и десериализует его
+// через Unmarshal, так как у этого GOOS нет syscall.Mmap.
+func MMap[K comparable, V any](path string) (*MMapView[K, V], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := Unmarshal[K, V](f)
+	if err != nil {
+		return nil, err
+	}
+	return &MMapView[K, V]{Map: m}, nil
+}
+
+// Close не держит никаких ресурсов на этой платформе.
+func (v *MMapView[K, V]) Close() error {
+	return nil
+}
@@ -0,0 +1,214 @@
+//go:build unix
+
+package hashmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// MMapView - read-only представление таблицы, memory-mapped прямо из
+// файла: Get/Range декодируют ключи и значения на лету из mmap-байтов,
+// не разворачивая файл в bmap[K,V] в куче. Это то, что нужно для таблиц,
+// которые не помещаются в GC heap целиком.
+type MMapView[K comparable, V any] struct {
+	data   []byte
+	header marshalHeader
+
+	bucketsOff  int
+	overflowOff int
+	recordSize  int
+	keysOff     int
+	valuesOff   int
+
+	hasher Hasher[K]
+}
+
+// MMap открывает файл, записанный (*Map[K, V]).Marshal, через mmap(2) и
+// возвращает по нему read-only представление.
+func MMap[K comparable, V any](path string) (*MMapView[K, V], error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := int(fi.Size())
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("hashmap: mmap %s: %w", path, err)
+	}
+
+	view, err := newMMapView[K, V](data)
+	if err != nil {
+		_ = syscall.Munmap(data)
+		return nil, err
+	}
+	return view, nil
+}
+
+func newMMapView[K comparable, V any](data []byte) (*MMapView[K, V], error) {
+	var header marshalHeader
+	headerSize := binary.Size(header)
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("hashmap: file too small to contain a header")
+	}
+	if err := binary.Read(bytes.NewReader(data[:headerSize]), binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Magic != marshalMagic {
+		return nil, fmt.Errorf("hashmap: bad magic %#x, not a Map file", header.Magic)
+	}
+	if header.Version != marshalVersion {
+		return nil, fmt.Errorf("hashmap: unsupported format version %d", header.Version)
+	}
+
+	keySize, err := fixedSize[K]()
+	if err != nil {
+		return nil, err
+	}
+	valueSize, err := fixedSize[V]()
+	if err != nil {
+		return nil, err
+	}
+	if int(header.KeySize) != keySize || int(header.ValueSize) != valueSize {
+		return nil, fmt.Errorf("hashmap: file key/value size %d/%d does not match %T/%T (%d/%d)",
+			header.KeySize, header.ValueSize, *new(K), *new(V), keySize, valueSize)
+	}
+
+	keysOff := bucketCnt
+	valuesOff := keysOff + bucketCnt*keySize
+	recordSize := bucketRecordSize(keySize, valueSize)
+
+	if err := validateHeaderCounts(header, int64(len(data)-headerSize), true, recordSize); err != nil {
+		return nil, err
+	}
+
+	return &MMapView[K, V]{
+		data:        data,
+		header:      header,
+		bucketsOff:  headerSize,
+		overflowOff: headerSize + int(header.NumBuckets)*recordSize,
+		recordSize:  recordSize,
+		keysOff:     keysOff,
+		valuesOff:   valuesOff,
+		hasher:      DefaultHasher[K](),
+	}, nil
+}
+
+// Close отменяет mmap. Представление нельзя использовать после Close.
+func (v *MMapView[K, V]) Close() error {
+	return syscall.Munmap(v.data)
+}
+
+// Len возвращает количество элементов в таблице.
+func (v *MMapView[K, V]) Len() int {
+	return int(v.header.Count)
+}
+
+func (v *MMapView[K, V]) record(off int) []byte {
+	return v.data[off : off+v.recordSize]
+}
+
+// overflowRef читает 1-based ссылку на overflow-бакет из конца записи.
+// Ссылка за пределами v.header.Overflow означает повреждённый файл (см.
+// readBucket в serialize.go, которая для того же поля при чтении через
+// Unmarshal уже проверяет его на это); здесь, раз у Get/Range нет канала
+// для возврата ошибки, такая ссылка трактуется как конец цепочки, а не
+// как повод читать за границами v.data.
+func (v *MMapView[K, V]) overflowRef(rec []byte) uint32 {
+	ref := binary.LittleEndian.Uint32(rec[v.recordSize-4:])
+	if uint64(ref) > v.header.Overflow {
+		return 0
+	}
+	return ref
+}
+
+func (v *MMapView[K, V]) decodeSlot(rec []byte, i int) (K, V, error) {
+	var key K
+	var value V
+	keySize := (v.valuesOff - v.keysOff) / bucketCnt
+	valueSize := (v.recordSize - 4 - v.valuesOff) / bucketCnt
+
+	kb := rec[v.keysOff+i*keySize : v.keysOff+(i+1)*keySize]
+	vb := rec[v.valuesOff+i*valueSize : v.valuesOff+(i+1)*valueSize]
+
+	if err := binary.Read(bytes.NewReader(kb), binary.LittleEndian, &key); err != nil {
+		return key, value, err
+	}
+	if err := binary.Read(bytes.NewReader(vb), binary.LittleEndian, &value); err != nil {
+		return key, value, err
+	}
+	return key, value, nil
+}
+
+// Get ищет значение по ключу, читая байты прямо из mmap.
+func (v *MMapView[K, V]) Get(key K) (V, bool) {
+	var zero V
+	hash := v.hasher.Hash(key, v.header.Seed)
+	top := tophash(hash)
+	idx := uint64(hash) & (v.header.NumBuckets - 1)
+	off := v.bucketsOff + int(idx)*v.recordSize
+
+	for {
+		rec := v.record(off)
+		for i := 0; i < bucketCnt; i++ {
+			if rec[i] != top {
+				continue
+			}
+			k, val, err := v.decodeSlot(rec, i)
+			if err == nil && k == key {
+				return val, true
+			}
+		}
+
+		ref := v.overflowRef(rec)
+		if ref == 0 {
+			return zero, false
+		}
+		off = v.overflowOff + int(ref-1)*v.recordSize
+	}
+}
+
+// Range проходит по всем парам ключ/значение в неопределённом порядке.
+func (v *MMapView[K, V]) Range(f func(key K, value V) bool) {
+	visit := func(off int) bool {
+		rec := v.record(off)
+		for i := 0; i < bucketCnt; i++ {
+			if rec[i] == 0 {
+				continue
+			}
+			k, val, err := v.decodeSlot(rec, i)
+			if err != nil {
+				continue
+			}
+			if !f(k, val) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for bi := uint64(0); bi < v.header.NumBuckets; bi++ {
+		off := v.bucketsOff + int(bi)*v.recordSize
+		if !visit(off) {
+			return
+		}
+		rec := v.record(off)
+		for ref := v.overflowRef(rec); ref != 0; {
+			off = v.overflowOff + int(ref-1)*v.recordSize
+			if !visit(off) {
+				return
+			}
+			ref = v.overflowRef(v.record(off))
+		}
+	}
+}
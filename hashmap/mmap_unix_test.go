@@ -0,0 +1,84 @@
+//go:build unix
+
+package hashmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMMapToleratesCorruptedOverflowRef corrupts a single bucket's trailing
+// overflow-ref field (the same field readBucket in serialize.go already
+// validates against len(pool) for Unmarshal) to point far outside
+// header.Overflow. The header itself is untouched and passes
+// validateHeaderCounts, so the corruption can only be caught while
+// actually walking the chain - Range/Get must stop cleanly instead of
+// slicing past len(data). The fallback MMap on non-unix platforms
+// (mmap_other.go) goes through Unmarshal, which already rejects this via
+// readBucket, so this is specific to the mmap(2)-backed view.
+func TestMMapToleratesCorruptedOverflowRef(t *testing.T) {
+	m := New[int32, int64](0)
+	const n = 3000 // достаточно, чтобы появились overflow-бакеты
+	for i := int32(0); i < n; i++ {
+		m.Put(i, int64(i)*7)
+	}
+
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	data := buf.Bytes()
+
+	var header marshalHeader
+	headerSize := binary.Size(header)
+	if err := binary.Read(bytes.NewReader(data[:headerSize]), binary.LittleEndian, &header); err != nil {
+		t.Fatalf("decode header: %v", err)
+	}
+
+	keySize, _ := fixedSize[int32]()
+	valueSize, _ := fixedSize[int64]()
+	recordSize := bucketRecordSize(keySize, valueSize)
+
+	corrupted := false
+	for bi := uint64(0); bi < header.NumBuckets; bi++ {
+		refOff := headerSize + int(bi)*recordSize + recordSize - 4
+		if binary.LittleEndian.Uint32(data[refOff:]) != 0 {
+			binary.LittleEndian.PutUint32(data[refOff:], 0x7fffffff)
+			corrupted = true
+			break
+		}
+	}
+	if !corrupted {
+		t.Fatalf("test setup is broken: no bucket in this table has an overflow chain to corrupt")
+	}
+
+	path := filepath.Join(t.TempDir(), "corrupt-overflow-ref.hmap")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write corrupted file: %v", err)
+	}
+
+	view, err := MMap[int32, int64](path)
+	if err != nil {
+		t.Fatalf("MMap: %v", err)
+	}
+	defer view.Close()
+
+	seen := 0
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Range panicked on a corrupted overflow ref: %v", r)
+			}
+		}()
+		view.Range(func(int32, int64) bool {
+			seen++
+			return true
+		})
+	}()
+	if seen == 0 || seen > n {
+		t.Fatalf("Range visited %d entries after a corrupted overflow ref, want a partial but sane result", seen)
+	}
+}
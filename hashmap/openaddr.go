@@ -0,0 +1,218 @@
+package hashmap
+
+// Package-level note on this file: bucketed Map (hashmap.go) chains
+// collisions through overflow pointers, which is friendly to very large
+// maps (a bucket miss only touches 8 slots) but costs a pointer chase per
+// overflow hop and wastes a [8]K/[8]V worth of memory once a bucket has
+// even a single resident. OpenAddrMap trades that for a single flat
+// slice: every key/value pair lives inline, so small value types (ints,
+// small structs) stay cache-friendly and allocate only when the table is
+// resized or rehashed.
+// The price is Robin Hood displacement on insert and a periodic rehash to
+// clean up tombstones left by Delete - see the *SmallValues benchmarks in
+// openaddr_bench_test.go for the actual numbers on this machine.
+
+// oaState - состояние слота в плоском массиве OpenAddrMap.
+type oaState uint8
+
+const (
+	oaEmpty oaState = iota
+	oaOccupied
+	oaTombstone
+)
+
+// oaSlot - один слот линейного пробирования: ключ и значение лежат
+// прямо в слоте (в отличие от bmap, где ключи/значения сгруппированы по
+// 8 отдельно от tophash). dist - дистанция от "домашнего" бакета ключа,
+// нужна для Robin Hood displacement при вставке.
+type oaSlot[K comparable, V any] struct {
+	state oaState
+	dist  int32
+	key   K
+	value V
+}
+
+// OpenAddrMap - хэш-таблица с открытой адресацией (линейное пробирование
+// + Robin Hood), альтернатива бакетированной Map для небольших типов
+// значений, где локальность памяти важнее, чем поведение на очень
+// больших объёмах данных.
+type OpenAddrMap[K comparable, V any] struct {
+	hasher     Hasher[K]
+	seed       uint64
+	slots      []oaSlot[K, V]
+	count      int
+	tombstones int
+}
+
+// maxLoadNum/maxLoadDen - порог заполнения (вместе с tombstones), после
+// которого таблица растёт вдвое (степень двойки, как и у bmap).
+const (
+	maxLoadNum = 9
+	maxLoadDen = 10
+)
+
+// NewOpenAddr создаёт пустую OpenAddrMap, заранее подготовленную под
+// hint элементов.
+func NewOpenAddr[K comparable, V any](hint int) *OpenAddrMap[K, V] {
+	capacity := nextPow2(hint)
+	if capacity < bucketCnt {
+		capacity = bucketCnt
+	}
+	return &OpenAddrMap[K, V]{
+		hasher: DefaultHasher[K](),
+		seed:   randomSeed(),
+		slots:  make([]oaSlot[K, V], capacity),
+	}
+}
+
+// nextPow2 возвращает наименьшую степень двойки, не меньшую n.
+func nextPow2(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// Get возвращает значение по ключу и флаг, было ли оно найдено.
+func (m *OpenAddrMap[K, V]) Get(key K) (V, bool) {
+	mask := uintptr(len(m.slots)) - 1
+	idx := uintptr(m.hasher.Hash(key, m.seed)) & mask
+
+	for {
+		slot := &m.slots[idx]
+		switch slot.state {
+		case oaEmpty:
+			var zero V
+			return zero, false
+		case oaOccupied:
+			if slot.key == key {
+				return slot.value, true
+			}
+		}
+		idx = (idx + 1) & mask
+	}
+}
+
+// Put добавляет или обновляет значение по ключу.
+func (m *OpenAddrMap[K, V]) Put(key K, value V) {
+	// Ключ может жить дальше по цепочке, чем первый tombstone или слот,
+	// который Robin Hood вставка заняла бы, поэтому сначала ищем его.
+	mask := uintptr(len(m.slots)) - 1
+	for idx := uintptr(m.hasher.Hash(key, m.seed)) & mask; m.slots[idx].state != oaEmpty; idx = (idx + 1) & mask {
+		if m.slots[idx].state == oaOccupied && m.slots[idx].key == key {
+			m.slots[idx].value = value
+			return
+		}
+	}
+
+	if uintptr(m.count+m.tombstones+1)*maxLoadDen > uintptr(len(m.slots))*maxLoadNum {
+		m.rehash(len(m.slots) * 2)
+	}
+	m.insert(oaSlot[K, V]{state: oaOccupied, key: key, value: value})
+}
+
+// insert выполняет Robin Hood вставку: если встреченный элемент "богаче"
+// (ближе к своему дому, чем пришедший элемент, - его dist меньше),
+// элементы меняются местами и вставка продолжается уже с вытесненным слотом. Это выравнивает
+// длины цепочек так, что ни одна не становится намного длиннее средней.
+func (m *OpenAddrMap[K, V]) insert(e oaSlot[K, V]) {
+	mask := uintptr(len(m.slots)) - 1
+	idx := uintptr(m.hasher.Hash(e.key, m.seed)) & mask
+	e.dist = 0
+
+	for {
+		slot := &m.slots[idx]
+
+		switch slot.state {
+		case oaEmpty:
+			*slot = e
+			m.count++
+			return
+		case oaTombstone:
+			*slot = e
+			m.count++
+			m.tombstones--
+			return
+		case oaOccupied:
+			if slot.key == e.key {
+				slot.value = e.value
+				return
+			}
+			if slot.dist < e.dist {
+				*slot, e = e, *slot
+			}
+		}
+
+		idx = (idx + 1) & mask
+		e.dist++
+	}
+}
+
+// Delete удаляет ключ из таблицы, если он там был, оставляя на его
+// месте tombstone. Накопившиеся tombstones удлиняют цепочки пробирования,
+// поэтому при их избытке таблица периодически перестраивается заново
+// (rehash без изменения размера), как и описано в задаче.
+func (m *OpenAddrMap[K, V]) Delete(key K) {
+	mask := uintptr(len(m.slots)) - 1
+	idx := uintptr(m.hasher.Hash(key, m.seed)) & mask
+
+	for {
+		slot := &m.slots[idx]
+		switch slot.state {
+		case oaEmpty:
+			return
+		case oaOccupied:
+			if slot.key == key {
+				var zeroK K
+				var zeroV V
+				slot.state = oaTombstone
+				slot.key = zeroK
+				slot.value = zeroV
+				m.count--
+				m.tombstones++
+
+				if m.tombstones*2 > len(m.slots) {
+					m.rehash(len(m.slots))
+				}
+				return
+			}
+		}
+		idx = (idx + 1) & mask
+	}
+}
+
+// Len возвращает количество элементов в таблице.
+func (m *OpenAddrMap[K, V]) Len() int {
+	return m.count
+}
+
+// Range проходит по всем парам ключ/значение в неопределённом порядке,
+// пока f не вернёт false.
+func (m *OpenAddrMap[K, V]) Range(f func(key K, value V) bool) {
+	for i := range m.slots {
+		slot := &m.slots[i]
+		if slot.state != oaOccupied {
+			continue
+		}
+		if !f(slot.key, slot.value) {
+			return
+		}
+	}
+}
+
+// rehash перестраивает таблицу с новым размером newCap (степень двойки),
+// перевставляя только живые записи и тем самым избавляясь от всех
+// tombstones.
+func (m *OpenAddrMap[K, V]) rehash(newCap int) {
+	old := m.slots
+	m.slots = make([]oaSlot[K, V], newCap)
+	m.count = 0
+	m.tombstones = 0
+
+	for i := range old {
+		if old[i].state == oaOccupied {
+			m.insert(oaSlot[K, V]{state: oaOccupied, key: old[i].key, value: old[i].value})
+		}
+	}
+}
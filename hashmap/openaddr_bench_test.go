@@ -0,0 +1,48 @@
+package hashmap
+
+import "testing"
+
+// Бенчмарки *SmallValues ниже попарно сравнивают OpenAddrMap и
+// бакетированную Map на Put и Get для int/int32 - маленьких типов значений, где локальность памяти должна
+// играть в пользу плоского массива OpenAddrMap.
+func BenchmarkOpenAddrPutSmallValues(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		m := NewOpenAddr[int, int32](benchKeySpace)
+		for k := 0; k < benchKeySpace; k++ {
+			m.Put(k, int32(k))
+		}
+	}
+}
+
+func BenchmarkBucketedMapPutSmallValues(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		m := New[int, int32](benchKeySpace)
+		for k := 0; k < benchKeySpace; k++ {
+			m.Put(k, int32(k))
+		}
+	}
+}
+
+func BenchmarkOpenAddrGetSmallValues(b *testing.B) {
+	m := NewOpenAddr[int, int32](benchKeySpace)
+	for k := 0; k < benchKeySpace; k++ {
+		m.Put(k, int32(k))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(i % benchKeySpace)
+	}
+}
+
+func BenchmarkBucketedMapGetSmallValues(b *testing.B) {
+	m := New[int, int32](benchKeySpace)
+	for k := 0; k < benchKeySpace; k++ {
+		m.Put(k, int32(k))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(i % benchKeySpace)
+	}
+}
@@ -0,0 +1,103 @@
+package hashmap
+
+import "testing"
+
+func TestOpenAddrPutGetDelete(t *testing.T) {
+	m := NewOpenAddr[string, int](0)
+
+	m.Put("a", 1)
+	m.Put("b", 2)
+	m.Put("a", 10)
+
+	if v, ok := m.Get("a"); !ok || v != 10 {
+		t.Fatalf("Get(a) = %d, %v, want 10, true", v, ok)
+	}
+	if v, ok := m.Get("b"); !ok || v != 2 {
+		t.Fatalf("Get(b) = %d, %v, want 2, true", v, ok)
+	}
+	if _, ok := m.Get("missing"); ok {
+		t.Fatalf("Get(missing) found a value, want not found")
+	}
+	if got, want := m.Len(), 2; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+
+	m.Delete("a")
+	if _, ok := m.Get("a"); ok {
+		t.Fatalf("Get(a) found a value after Delete")
+	}
+	if got, want := m.Len(), 1; got != want {
+		t.Fatalf("Len() = %d after Delete, want %d", got, want)
+	}
+}
+
+func TestOpenAddrGrowthPreservesAllEntries(t *testing.T) {
+	m := NewOpenAddr[int, int](0)
+
+	const n = 5000
+	for i := 0; i < n; i++ {
+		m.Put(i, i*2)
+	}
+
+	if got := m.Len(); got != n {
+		t.Fatalf("Len() = %d, want %d", got, n)
+	}
+	for i := 0; i < n; i++ {
+		v, ok := m.Get(i)
+		if !ok || v != i*2 {
+			t.Fatalf("Get(%d) = %d, %v, want %d, true", i, v, ok, i*2)
+		}
+	}
+}
+
+func TestOpenAddrTombstoneRehash(t *testing.T) {
+	m := NewOpenAddr[int, int](0)
+
+	const n = 600 // таблица под n ключей занимает 1024 слота, n > 1024/2
+	for i := 0; i < n; i++ {
+		m.Put(i, i)
+	}
+	// Раунд за раундом удаляем все ключи и вставляем их обратно: tombstones
+	// успевают превысить половину слотов, и Delete делает rehash без
+	// изменения размера.
+	for round := 0; round < 10; round++ {
+		for i := 0; i < n; i++ {
+			m.Delete(i)
+		}
+		for i := 0; i < n; i++ {
+			m.Put(i, i)
+		}
+	}
+
+	if got, want := m.Len(), n; got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	for i := 0; i < n; i++ {
+		if v, ok := m.Get(i); !ok || v != i {
+			t.Fatalf("Get(%d) = %d, %v, want %d, true", i, v, ok, i)
+		}
+	}
+}
+
+func TestOpenAddrRange(t *testing.T) {
+	m := NewOpenAddr[int, int](0)
+	want := map[int]int{1: 10, 2: 20, 3: 30}
+	for k, v := range want {
+		m.Put(k, v)
+	}
+
+	got := make(map[int]int)
+	m.Range(func(k, v int) bool {
+		got[k] = v
+		return true
+	})
+
+	if len(got) != len(want) {
+		t.Fatalf("Range visited %d entries, want %d", len(got), len(want))
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("Range missed %d=%d, got %v", k, v, got)
+		}
+	}
+}
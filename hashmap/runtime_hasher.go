@@ -0,0 +1,79 @@
+package hashmap
+
+import "unsafe"
+
+// RuntimeHasher обслуживает любой comparable-тип K, для которого у нас
+// нет специализированной реализации: вместо того чтобы писать свою
+// хэш-функцию "в лоб" (и рисковать нарушить свойства #1/#4 из main.go),
+// мы достаём готовый хэшер, который рантайм Go уже сгенерировал для
+// этого типа под капотом у обычного map[K]struct{} (подход из
+// dolthub/maphash). Тип ниже - локальная копия нужных полей
+// internal/abi.Type/MapType: этот пакет нельзя импортировать напрямую
+// извне стандартной библиотеки, но его layout стабилен в рамках минорной
+// версии Go, на которую ориентирован модуль (go.mod).
+type RuntimeHasher[K comparable] struct {
+	hash func(unsafe.Pointer, uintptr) uintptr
+}
+
+// NewRuntimeHasher создаёт RuntimeHasher для K. Сам конструктор не
+// паникует; если K - интерфейсный тип, а ключ хранит не comparable
+// значение, паникует Hash (так же, как паникует вставка такого ключа во
+// встроенный map[K]struct{}).
+func NewRuntimeHasher[K comparable]() *RuntimeHasher[K] {
+	return &RuntimeHasher[K]{hash: runtimeHasher[K]()}
+}
+
+// Hash вызывает хэшер рантайма Go, передавая ему seed в качестве
+// "затравки" (эквивалент второго аргумента runtime hashfn).
+func (r *RuntimeHasher[K]) Hash(key K, seed uint64) uint64 {
+	return uint64(r.hash(unsafe.Pointer(&key), uintptr(seed)))
+}
+
+// emptyInterface - layout значения типа any: слово с указателем на
+// дескриптор типа и слово с данными.
+type emptyInterface struct {
+	typ  unsafe.Pointer
+	data unsafe.Pointer
+}
+
+// abiType - копия начала internal/abi.Type (общая часть любого
+// дескриптора типа, в том числе map).
+type abiType struct {
+	size       uintptr
+	ptrBytes   uintptr
+	hash       uint32
+	tflag      uint8
+	align      uint8
+	fieldAlign uint8
+	kind       uint8
+	equal      func(unsafe.Pointer, unsafe.Pointer) bool
+	gcdata     *byte
+	str        int32
+	ptrToThis  int32
+}
+
+// abiMapType - копия internal/abi.MapType: дескриптор типа map встроен с
+// abiType в начале, дальше идут поля, специфичные для map, включая
+// указатель на функцию хэширования ключа.
+type abiMapType struct {
+	abiType
+	key        unsafe.Pointer
+	elem       unsafe.Pointer
+	bucket     unsafe.Pointer
+	hasher     func(unsafe.Pointer, uintptr) uintptr
+	keySize    uint8
+	valueSize  uint8
+	bucketSize uint16
+	flags      uint32
+}
+
+// runtimeHasher возвращает функцию-хэшер, которой рантайм Go пользуется
+// для map[K]struct{}. Дескриптор типа такой map - это MapType, первое
+// поле которого (abiType) лежит по тому же адресу, что и сам дескриптор,
+// поэтому достаточно привести указатель на тип к *abiMapType.
+func runtimeHasher[K comparable]() func(unsafe.Pointer, uintptr) uintptr {
+	var m any = make(map[K]struct{})
+	iface := (*emptyInterface)(unsafe.Pointer(&m))
+	mapType := (*abiMapType)(iface.typ)
+	return mapType.hasher
+}
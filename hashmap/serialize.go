@@ -0,0 +1,318 @@
+package hashmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Этот файл добавляет бинарный формат для Map: Marshal/Unmarshal пишут и
+// читают таблицу через io.Writer/io.Reader, а MMap (mmap_unix.go /
+// mmap_other.go) даёт read-only доступ прямо к файлу на диске, без
+// разворачивания всей таблицы в куче.
+//
+// Формат годится только для K/V, которые encoding/binary умеет писать как
+// значение фиксированного размера (binary.Size(zero) >= 0) - то есть без
+// строк, срезов, map и указателей, а также без int/uint/uintptr, размер
+// которых зависит от платформы. Это осознанное ограничение: без него
+// не получить ни endianness-independent раскладку, ни zero-copy чтение
+// из mmap.
+//
+// Схема файла (всё число - little-endian):
+//
+//	marshalHeader
+//	buckets[NumBuckets]  - основной массив бакетов
+//	overflow[Overflow]   - overflow-бакеты, на которые ссылаются buckets
+//	                       (и друг на друга) по 1-based индексу вместо
+//	                       указателя: 0 значит "нет overflow".
+//
+// Каждый бакет: tophash[8]uint8, keys[8]K, values[8]V, overflowRef uint32.
+
+const (
+	marshalMagic   = 0x31504d48 // "HMP1" в representation little-endian
+	marshalVersion = 1
+)
+
+// maxMarshalB - жёсткий предел на B, прочитанный из файла, на случай если
+// узнать реальный размер оставшихся данных не получается (см.
+// readerSize): 2^32 бакетов уже не соответствует ни одной настоящей
+// таблице, так что дальше можно честно отказать, а не пытаться выделить
+// память под них.
+const maxMarshalB = 32
+
+// bucketRecordSize возвращает размер одного бакета на диске в байтах:
+// tophash[8] + keys[8]*keySize + values[8]*valueSize + overflowRef(4).
+// Использует тот же layout, что и writeBucket/readBucket, и нужен
+// MMapView (mmap_unix.go), чтобы не дублировать формулу.
+func bucketRecordSize(keySize, valueSize int) int {
+	return bucketCnt + bucketCnt*keySize + bucketCnt*valueSize + 4
+}
+
+// readerSize пытается узнать количество байт, оставшихся в r, не читая их:
+// через Len() у *bytes.Reader/*bytes.Buffer или через Seek у файлов. Если
+// ни то ни другое не поддерживается (например, r - это сетевой поток),
+// возвращает ok == false.
+func readerSize(r io.Reader) (size int64, ok bool) {
+	switch rr := r.(type) {
+	case interface{ Len() int }:
+		return int64(rr.Len()), true
+	case io.Seeker:
+		cur, err := rr.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		end, err := rr.Seek(0, io.SeekEnd)
+		if err != nil {
+			return 0, false
+		}
+		if _, err := rr.Seek(cur, io.SeekStart); err != nil {
+			return 0, false
+		}
+		return end - cur, true
+	default:
+		return 0, false
+	}
+}
+
+// validateHeaderCounts проверяет NumBuckets/Overflow из заголовка, прежде
+// чем доверить их под make(): NumBuckets обязан совпадать с 2^B (именно
+// так Marshal его и пишет), а если размер оставшихся данных известен -
+// оба значения не должны описывать больше бакетов, чем в принципе может
+// поместиться в оставшихся байтах. Без этого truncated или specially
+// crafted файл мог бы запросить терабайты памяти вместо понятной ошибки.
+func validateHeaderCounts(header marshalHeader, size int64, sizeKnown bool, recSize int) error {
+	if header.B > maxMarshalB {
+		return fmt.Errorf("hashmap: corrupt file, B=%d exceeds the maximum supported %d", header.B, maxMarshalB)
+	}
+	wantBuckets := uint64(1) << header.B
+	if header.NumBuckets != wantBuckets {
+		return fmt.Errorf("hashmap: corrupt file, NumBuckets=%d does not match B=%d (want %d)", header.NumBuckets, header.B, wantBuckets)
+	}
+	if !sizeKnown {
+		return nil
+	}
+
+	maxRecords := uint64(size) / uint64(recSize)
+	if header.NumBuckets > maxRecords || header.Overflow > maxRecords-header.NumBuckets {
+		return fmt.Errorf("hashmap: corrupt file, header claims %d buckets and %d overflow buckets but only %d bytes remain",
+			header.NumBuckets, header.Overflow, size)
+	}
+	return nil
+}
+
+// marshalHeader - заголовок файла. Поля фиксированного размера и пишутся
+// в этом порядке через encoding/binary, поэтому заголовок одинаково
+// читается что на little-endian, что на big-endian машине.
+type marshalHeader struct {
+	Magic      uint32
+	Version    uint8
+	B          uint8
+	KeySize    uint16
+	ValueSize  uint16
+	_          uint16 // выравнивание, зарезервировано
+	Count      uint64
+	Seed       uint64
+	NumBuckets uint64
+	Overflow   uint64
+}
+
+// fixedSize возвращает размер в байтах, который займёт значение типа T в
+// формате encoding/binary, либо ошибку, если T - не fixed-size тип.
+func fixedSize[T any]() (int, error) {
+	var zero T
+	n := binary.Size(zero)
+	if n < 0 {
+		return 0, fmt.Errorf("hashmap: %T is not a fixed-size type supported by Marshal/MMap", zero)
+	}
+	return n, nil
+}
+
+// Marshal пишет таблицу в w в формате, описанном выше. Перед записью
+// таблица буквально "дорастает" - все отложенные эвакуации старых
+// бакетов выполняются сразу, чтобы на диск попало согласованное,
+// статичное состояние.
+func (m *Map[K, V]) Marshal(w io.Writer) error {
+	if m.flags&hashWriting != 0 {
+		panic("hashmap: concurrent map read and map write")
+	}
+	m.flags |= hashWriting
+	defer func() { m.flags &^= hashWriting }()
+
+	for m.oldbuckets != nil {
+		m.evacuate(m.nevacuate)
+	}
+
+	keySize, err := fixedSize[K]()
+	if err != nil {
+		return err
+	}
+	valueSize, err := fixedSize[V]()
+	if err != nil {
+		return err
+	}
+
+	if m.buckets == nil {
+		m.buckets = make([]bmap[K, V], 1)
+		m.B = 0
+	}
+
+	overflow := collectOverflowBuckets(m.buckets)
+
+	header := marshalHeader{
+		Magic:      marshalMagic,
+		Version:    marshalVersion,
+		B:          m.B,
+		KeySize:    uint16(keySize),
+		ValueSize:  uint16(valueSize),
+		Count:      uint64(m.count),
+		Seed:       m.seed,
+		NumBuckets: uint64(len(m.buckets)),
+		Overflow:   uint64(len(overflow)),
+	}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return err
+	}
+
+	index := make(map[*bmap[K, V]]uint32, len(overflow))
+	for i, b := range overflow {
+		index[b] = uint32(i + 1)
+	}
+
+	for bi := range m.buckets {
+		if err := writeBucket(w, &m.buckets[bi], index); err != nil {
+			return err
+		}
+	}
+	for _, b := range overflow {
+		if err := writeBucket(w, b, index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectOverflowBuckets обходит цепочки overflow у каждого бакета в
+// buckets и возвращает их в порядке обхода. Индекс элемента в
+// возвращенном срезе (плюс один) - это ссылка, которой кодируется
+// указатель overflow в writeBucket.
+func collectOverflowBuckets[K comparable, V any](buckets []bmap[K, V]) []*bmap[K, V] {
+	var overflow []*bmap[K, V]
+	for bi := range buckets {
+		b := buckets[bi].overflow
+		for b != nil {
+			overflow = append(overflow, b)
+			b = b.overflow
+		}
+	}
+	return overflow
+}
+
+// writeBucket пишет один бакет: tophash, ключи, значения и 1-based
+// ссылку на overflow-бакет (0, если его нет).
+func writeBucket[K comparable, V any](w io.Writer, b *bmap[K, V], index map[*bmap[K, V]]uint32) error {
+	if err := binary.Write(w, binary.LittleEndian, b.tophash); err != nil {
+		return err
+	}
+	for i := 0; i < bucketCnt; i++ {
+		if err := binary.Write(w, binary.LittleEndian, b.keys[i]); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < bucketCnt; i++ {
+		if err := binary.Write(w, binary.LittleEndian, b.values[i]); err != nil {
+			return err
+		}
+	}
+	return binary.Write(w, binary.LittleEndian, index[b.overflow])
+}
+
+// Unmarshal читает таблицу, записанную Marshal, из r.
+func Unmarshal[K comparable, V any](r io.Reader) (*Map[K, V], error) {
+	var header marshalHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Magic != marshalMagic {
+		return nil, fmt.Errorf("hashmap: bad magic %#x, not a Map file", header.Magic)
+	}
+	if header.Version != marshalVersion {
+		return nil, fmt.Errorf("hashmap: unsupported format version %d", header.Version)
+	}
+
+	keySize, err := fixedSize[K]()
+	if err != nil {
+		return nil, err
+	}
+	valueSize, err := fixedSize[V]()
+	if err != nil {
+		return nil, err
+	}
+	if int(header.KeySize) != keySize || int(header.ValueSize) != valueSize {
+		return nil, fmt.Errorf("hashmap: file key/value size %d/%d does not match %T/%T (%d/%d)",
+			header.KeySize, header.ValueSize, *new(K), *new(V), keySize, valueSize)
+	}
+
+	size, sizeKnown := readerSize(r)
+	if err := validateHeaderCounts(header, size, sizeKnown, bucketRecordSize(keySize, valueSize)); err != nil {
+		return nil, err
+	}
+
+	m := &Map[K, V]{
+		hasher: DefaultHasher[K](),
+		seed:   header.Seed,
+		B:      header.B,
+		count:  int(header.Count),
+	}
+	m.buckets = make([]bmap[K, V], header.NumBuckets)
+
+	overflow := make([]*bmap[K, V], header.Overflow)
+	for i := range overflow {
+		overflow[i] = &bmap[K, V]{}
+	}
+
+	for bi := range m.buckets {
+		if err := readBucket(r, &m.buckets[bi], overflow); err != nil {
+			return nil, err
+		}
+	}
+	for _, b := range overflow {
+		if err := readBucket(r, b, overflow); err != nil {
+			return nil, err
+		}
+	}
+	m.noverflow = uint16(len(overflow))
+
+	return m, nil
+}
+
+// readBucket читает один бакет, записанный writeBucket, и привязывает
+// его overflow-ссылку к уже выделенным бакетам в pool.
+func readBucket[K comparable, V any](r io.Reader, b *bmap[K, V], pool []*bmap[K, V]) error {
+	if err := binary.Read(r, binary.LittleEndian, &b.tophash); err != nil {
+		return err
+	}
+	for i := 0; i < bucketCnt; i++ {
+		if err := binary.Read(r, binary.LittleEndian, &b.keys[i]); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < bucketCnt; i++ {
+		if err := binary.Read(r, binary.LittleEndian, &b.values[i]); err != nil {
+			return err
+		}
+	}
+
+	var ref uint32
+	if err := binary.Read(r, binary.LittleEndian, &ref); err != nil {
+		return err
+	}
+	if ref == 0 {
+		return nil
+	}
+	if int(ref) > len(pool) {
+		return fmt.Errorf("hashmap: corrupt file, overflow reference %d out of range", ref)
+	}
+	b.overflow = pool[ref-1]
+	return nil
+}
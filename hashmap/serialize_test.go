@@ -0,0 +1,186 @@
+package hashmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarshalUnmarshalRoundTrip(t *testing.T) {
+	m := New[int32, int64](0)
+	const n = 3000 // достаточно, чтобы появились overflow-бакеты
+	for i := int32(0); i < n; i++ {
+		m.Put(i, int64(i)*7)
+	}
+	m.Delete(5)
+
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	m2, err := Unmarshal[int32, int64](&buf)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got, want := m2.Len(), m.Len(); got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	for i := int32(0); i < n; i++ {
+		want, wantOK := m.Get(i)
+		got, gotOK := m2.Get(i)
+		if got != want || gotOK != wantOK {
+			t.Fatalf("Get(%d) = %d, %v, want %d, %v", i, got, gotOK, want, wantOK)
+		}
+	}
+}
+
+func TestUnmarshalRejectsTypeMismatch(t *testing.T) {
+	m := New[int32, int64](0)
+	m.Put(1, 2)
+
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if _, err := Unmarshal[int64, int64](bytes.NewReader(buf.Bytes())); err == nil {
+		t.Fatalf("Unmarshal with a mismatched key type succeeded, want an error")
+	}
+}
+
+func TestUnmarshalRejectsBadMagic(t *testing.T) {
+	if _, err := Unmarshal[int32, int64](bytes.NewReader(make([]byte, 64))); err == nil {
+		t.Fatalf("Unmarshal of garbage data succeeded, want an error")
+	}
+}
+
+func TestUnmarshalRejectsImplausibleBucketCounts(t *testing.T) {
+	m := New[int32, int64](0)
+	m.Put(1, 2)
+
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	data := buf.Bytes()
+
+	var header marshalHeader
+	headerSize := binary.Size(header)
+	if err := binary.Read(bytes.NewReader(data[:headerSize]), binary.LittleEndian, &header); err != nil {
+		t.Fatalf("decode header: %v", err)
+	}
+	header.B = 40
+	header.NumBuckets = 1 << 40
+
+	var corrupted bytes.Buffer
+	if err := binary.Write(&corrupted, binary.LittleEndian, header); err != nil {
+		t.Fatalf("encode header: %v", err)
+	}
+	corrupted.Write(data[headerSize:])
+
+	if _, err := Unmarshal[int32, int64](bytes.NewReader(corrupted.Bytes())); err == nil {
+		t.Fatalf("Unmarshal with an implausible bucket count succeeded, want an error")
+	}
+}
+
+func TestUnmarshalRejectsTruncatedBody(t *testing.T) {
+	m := New[int32, int64](0)
+	for i := int32(0); i < 100; i++ {
+		m.Put(i, int64(i))
+	}
+
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	truncated := buf.Bytes()[:buf.Len()/2]
+	if _, err := Unmarshal[int32, int64](bytes.NewReader(truncated)); err == nil {
+		t.Fatalf("Unmarshal of a truncated file succeeded, want an error")
+	}
+}
+
+func TestMarshalRejectsNonFixedSizeTypes(t *testing.T) {
+	m := New[string, int](0)
+	m.Put("a", 1)
+
+	if err := m.Marshal(&bytes.Buffer{}); err == nil {
+		t.Fatalf("Marshal of a Map[string, int] succeeded, want an error (string is not fixed-size)")
+	}
+}
+
+func TestMMapReadsBackWhatWasMarshaled(t *testing.T) {
+	m := New[int32, int64](0)
+	const n = 2000
+	for i := int32(0); i < n; i++ {
+		m.Put(i, int64(i)*3)
+	}
+
+	path := filepath.Join(t.TempDir(), "table.hmap")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := m.Marshal(f); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	view, err := MMap[int32, int64](path)
+	if err != nil {
+		t.Fatalf("MMap: %v", err)
+	}
+	defer view.Close()
+
+	if got, want := view.Len(), m.Len(); got != want {
+		t.Fatalf("Len() = %d, want %d", got, want)
+	}
+	for i := int32(0); i < n; i++ {
+		want, _ := m.Get(i)
+		got, ok := view.Get(i)
+		if !ok || got != want {
+			t.Fatalf("Get(%d) = %d, %v, want %d, true", i, got, ok, want)
+		}
+	}
+	if _, ok := view.Get(int32(n + 1)); ok {
+		t.Fatalf("Get(%d) found a value, want not found", n+1)
+	}
+
+	seen := make(map[int32]int64, n)
+	view.Range(func(k int32, v int64) bool {
+		seen[k] = v
+		return true
+	})
+	if len(seen) != n {
+		t.Fatalf("Range visited %d entries, want %d", len(seen), n)
+	}
+}
+
+func TestMMapRejectsTruncatedFile(t *testing.T) {
+	m := New[int32, int64](0)
+	const n = 2000
+	for i := int32(0); i < n; i++ {
+		m.Put(i, int64(i)*3)
+	}
+
+	var buf bytes.Buffer
+	if err := m.Marshal(&buf); err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "truncated.hmap")
+	if err := os.WriteFile(path, buf.Bytes()[:buf.Len()/2], 0o600); err != nil {
+		t.Fatalf("write truncated file: %v", err)
+	}
+
+	if view, err := MMap[int32, int64](path); err == nil {
+		view.Close()
+		t.Fatalf("MMap of a truncated file succeeded, want an error")
+	}
+}